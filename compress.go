@@ -0,0 +1,32 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// wrapCompressor sits between a Sink and the underlying writer (stdout, a
+// response body, ...), so compression is orthogonal to output format.
+func wrapCompressor(w io.Writer, compress string) (io.WriteCloser, error) {
+	switch compress {
+	case "", "none":
+		return nopWriteCloser{w}, nil
+	case "gzip":
+		return gzip.NewWriter(w), nil
+	case "zstd":
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown compression %q", compress)
+	}
+}
+
+// nopWriteCloser adapts a plain io.Writer (e.g. os.Stdout) to io.WriteCloser
+// so the "none" compression case can be handled uniformly with the others.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
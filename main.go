@@ -39,15 +39,32 @@ func main() {
 			var err error
 			streaming, _ := cmd.Flags().GetBool("streaming")
 			streamingString, _ := cmd.Flags().GetBool("streaming-string")
+			batched, _ := cmd.Flags().GetBool("batched")
+			batchSize, _ := cmd.Flags().GetInt("batch-size")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			format, _ := cmd.Flags().GetString("format")
+			compress, _ := cmd.Flags().GetString("compress")
 			triggerGC, _ := cmd.Flags().GetBool("trigger-gc")
+			metricsInterval, _ := cmd.Flags().GetDuration("metrics-interval")
+			metricsJSON, _ := cmd.Flags().GetString("metrics-json")
+			driver, _ := cmd.Flags().GetString("driver")
+			dsn, _ := cmd.Flags().GetString("dsn")
+			query, _ := cmd.Flags().GetString("query")
+			templateFile, _ := cmd.Flags().GetString("template-file")
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
-			go monitorHeapSize(ctx, triggerGC)
+			go monitorMetrics(ctx, triggerGC, metricsInterval)
 
 			start := time.Now()
-			if streamingString {
+			if query != "" {
+				err = generateStreamQuery(driver, dsn, query, templateFile)
+			} else if format != "" && format != "markdown" || compress != "" && compress != "none" {
+				err = generateStreamingSink(format, compress)
+			} else if batched {
+				err = generateBatchedStreamingMarkdown(batchSize, concurrency)
+			} else if streamingString {
 				err = generateStreamingStringMarkdown()
 			} else if streaming {
 				err = generateStreamingMarkdown()
@@ -56,16 +73,47 @@ func main() {
 			}
 			elapsed := time.Since(start)
 			log.Printf("Time elapsed: %s\n", elapsed)
+
+			if metricsJSON != "" {
+				if jsonErr := writeMetricsJSON(metricsJSON); jsonErr != nil {
+					log.Printf("Failed to write metrics JSON: %s\n", jsonErr)
+				}
+			}
+
 			cobra.CheckErr(err)
 		},
 	}
 
 	generateCmd.Flags().Bool("streaming", false, "Whether to stream the data from the DB or not")
 	generateCmd.Flags().Bool("streaming-string", false, "Whether to stream the data from the DB as a string or not")
+	generateCmd.Flags().Bool("batched", false, "Whether to use the batched, worker-pool streaming mode")
+	generateCmd.Flags().Int("batch-size", 1000, "Number of rows per batch in batched streaming mode")
+	generateCmd.Flags().Int("concurrency", 4, "Number of render worker goroutines in batched streaming mode")
+	generateCmd.Flags().String("format", "markdown", "Output format: markdown, jsonl, csv, parquet")
+	generateCmd.Flags().String("compress", "none", "Output compression: none, gzip, zstd")
+	generateCmd.Flags().Duration("metrics-interval", 0, "How often to dump a metrics table to stderr (0 disables)")
+	generateCmd.Flags().String("metrics-json", "", "Path to write a final JSON metrics summary to (empty disables)")
+	generateCmd.Flags().String("driver", "sqlite3", "database/sql driver to use with --query")
+	generateCmd.Flags().String("dsn", "users.db", "Data source name to use with --query")
+	generateCmd.Flags().String("query", "", "Arbitrary SQL query to stream through --template-file instead of the built-in User pipeline")
+	generateCmd.Flags().String("template-file", "", "Template file to execute against --query results (defaults to the built-in markdown table)")
 	generateCmd.Flags().Bool("trigger-gc", false, "Whether to trigger a GC before measuring max heap")
 
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the generated table over HTTP with chunked streaming",
+		Run: func(cmd *cobra.Command, args []string) {
+			addr, _ := cmd.Flags().GetString("addr")
+			err := runServe(context.Background(), addr)
+			cobra.CheckErr(err)
+		},
+	}
+
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on")
+
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(serveCmd)
 
 	err := rootCmd.Execute()
 	cobra.CheckErr(err)
@@ -37,6 +37,7 @@ func generateMarkdown() error {
 		if err := rows.Scan(&user.Email, &user.FirstName, &user.LastName, &user.Address, &user.City, &user.Zip); err != nil {
 			return err
 		}
+		recordRowProcessed()
 		totalSize += int(unsafe.Sizeof(user))
 		users = append(users, user)
 	}
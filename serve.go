@@ -0,0 +1,209 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// userColumns whitelists the columns callers may filter on via ?where=, so
+// query params can never smuggle arbitrary SQL into the query.
+var userColumns = map[string]bool{
+	"email":      true,
+	"first_name": true,
+	"last_name":  true,
+	"address":    true,
+	"city":       true,
+	"zip":        true,
+}
+
+// extToFormat maps the file extension on the request path to a Sink format.
+var extToFormat = map[string]string{
+	".md":    "markdown",
+	".csv":   "csv",
+	".jsonl": "jsonl",
+}
+
+// usersHandler streams `SELECT ... FROM users` straight to the response,
+// flushing after every batch so clients see progressive output instead of
+// waiting for the whole query to finish.
+func usersHandler(w http.ResponseWriter, r *http.Request) {
+	format, ok := extToFormat[path.Ext(r.URL.Path)]
+	if !ok {
+		http.Error(w, "unsupported format, expected one of .md, .csv, .jsonl", http.StatusNotFound)
+		return
+	}
+
+	query, args, err := buildUsersQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := sql.Open("sqlite3", "users.db")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(r.Context(), query, args...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var out writeFlusher = noFlushWriter{w}
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gzipFlushWriter{gz: gz, w: w}
+	}
+
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+
+	sink, err := newSink(format, out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := sink.WriteHeader(); err != nil {
+		return
+	}
+	sink.Flush()
+	out.Flush()
+
+	const flushEvery = 1000
+	rowCount := 0
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.Email, &user.FirstName, &user.LastName, &user.Address, &user.City, &user.Zip); err != nil {
+			return
+		}
+		if err := sink.WriteRow(user); err != nil {
+			return
+		}
+		rowCount++
+		if rowCount%flushEvery == 0 {
+			sink.Flush()
+			out.Flush()
+		}
+	}
+	sink.Close()
+	out.Flush()
+}
+
+// buildUsersQuery turns limit/offset/where query params into a parameterized
+// SQL query. where is a comma-separated list of column:value pairs, e.g.
+// "city:Berlin,zip:90210"; unknown columns are rejected.
+func buildUsersQuery(params map[string][]string) (string, []interface{}, error) {
+	query := `SELECT email, first_name, last_name, address, city, zip FROM users`
+	var args []interface{}
+
+	if whereParam := firstParam(params, "where"); whereParam != "" {
+		var clauses []string
+		for _, pair := range strings.Split(whereParam, ",") {
+			col, val, ok := strings.Cut(pair, ":")
+			if !ok || !userColumns[col] {
+				return "", nil, fmt.Errorf("invalid where column in %q", pair)
+			}
+			clauses = append(clauses, col+" = ?")
+			args = append(args, val)
+		}
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+
+	if limitParam := firstParam(params, "limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 0 {
+			return "", nil, fmt.Errorf("invalid limit %q", limitParam)
+		}
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	if offsetParam := firstParam(params, "offset"); offsetParam != "" {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return "", nil, fmt.Errorf("invalid offset %q", offsetParam)
+		}
+		query += fmt.Sprintf(" OFFSET %d", offset)
+	}
+
+	return query, args, nil
+}
+
+func firstParam(params map[string][]string, name string) string {
+	if vs, ok := params[name]; ok && len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// writeFlusher lets usersHandler flush progressive output regardless of
+// whether gzip compression is in play.
+type writeFlusher interface {
+	Write(p []byte) (int, error)
+	Flush()
+}
+
+type noFlushWriter struct {
+	w http.ResponseWriter
+}
+
+func (n noFlushWriter) Write(p []byte) (int, error) {
+	return n.w.Write(p)
+}
+
+func (n noFlushWriter) Flush() {
+	if f, ok := n.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// gzipFlushWriter flushes both the gzip writer (to push buffered bytes out)
+// and the underlying http.Flusher (to push them onto the wire).
+type gzipFlushWriter struct {
+	gz *gzip.Writer
+	w  http.ResponseWriter
+}
+
+func (g gzipFlushWriter) Write(p []byte) (int, error) {
+	return g.gz.Write(p)
+}
+
+func (g gzipFlushWriter) Flush() {
+	g.gz.Flush()
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func runServe(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users.md", usersHandler)
+	mux.HandleFunc("/users.csv", usersHandler)
+	mux.HandleFunc("/users.jsonl", usersHandler)
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	err := srv.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const markdownHeader = `
+| Email | First Name | Last Name | Address | City | Zip |
+|-------|------------|-----------|---------|------|-----|
+`
+
+const markdownRowTemplate = `| {{.Email}} | {{.FirstName}} | {{.LastName}} | {{.Address}} | {{.City}} | {{.Zip}} |
+`
+
+// Sink is the write side of the streaming pipeline: it turns a stream of
+// User rows into some on-disk or on-wire representation. Implementations
+// are not expected to be safe for concurrent use; the pipeline drives each
+// sink from a single goroutine.
+type Sink interface {
+	WriteHeader() error
+	WriteRow(user User) error
+	// Flush pushes any rows buffered inside the sink out to the underlying
+	// writer, so callers doing incremental writes (e.g. the HTTP handler)
+	// see progressive output instead of only getting it on Close.
+	Flush() error
+	Close() error
+}
+
+// newSink builds the Sink for the requested output format, writing to w.
+func newSink(format string, w io.Writer) (Sink, error) {
+	switch format {
+	case "", "markdown":
+		return newMarkdownSink(w)
+	case "jsonl":
+		return newJSONLSink(w), nil
+	case "csv":
+		return newCSVSink(w), nil
+	case "parquet":
+		return newParquetSink(w)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// markdownSink writes the markdown table header once, then renders each row
+// through markdownRowTemplate, one Execute call per WriteRow.
+type markdownSink struct {
+	w    *bufio.Writer
+	tmpl *template.Template
+}
+
+func newMarkdownSink(w io.Writer) (*markdownSink, error) {
+	tmpl, err := template.New("markdown-row").Parse(markdownRowTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &markdownSink{w: bufio.NewWriter(w), tmpl: tmpl}, nil
+}
+
+func (s *markdownSink) WriteHeader() error {
+	_, err := s.w.WriteString(markdownHeader)
+	return err
+}
+
+func (s *markdownSink) WriteRow(user User) error {
+	return s.tmpl.Execute(s.w, user)
+}
+
+func (s *markdownSink) Flush() error {
+	return s.w.Flush()
+}
+
+func (s *markdownSink) Close() error {
+	return s.w.Flush()
+}
+
+// jsonlSink emits one JSON object per line.
+type jsonlSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLSink(w io.Writer) *jsonlSink {
+	bw := bufio.NewWriter(w)
+	return &jsonlSink{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (s *jsonlSink) WriteHeader() error {
+	return nil
+}
+
+func (s *jsonlSink) WriteRow(user User) error {
+	return s.enc.Encode(user)
+}
+
+func (s *jsonlSink) Flush() error {
+	return s.w.Flush()
+}
+
+func (s *jsonlSink) Close() error {
+	return s.w.Flush()
+}
+
+// csvSink emits a header row followed by one CSV record per user.
+type csvSink struct {
+	w *csv.Writer
+}
+
+func newCSVSink(w io.Writer) *csvSink {
+	return &csvSink{w: csv.NewWriter(w)}
+}
+
+func (s *csvSink) WriteHeader() error {
+	return s.w.Write([]string{"email", "first_name", "last_name", "address", "city", "zip"})
+}
+
+func (s *csvSink) WriteRow(user User) error {
+	return s.w.Write([]string{user.Email, user.FirstName, user.LastName, user.Address, user.City, user.Zip})
+}
+
+func (s *csvSink) Flush() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *csvSink) Close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// parquetUserRow mirrors User with the struct tags parquet-go needs to infer
+// the schema; it is kept separate so User itself stays free of format-specific tags.
+type parquetUserRow struct {
+	Email     string `parquet:"name=email, type=BYTE_ARRAY, convertedtype=UTF8"`
+	FirstName string `parquet:"name=first_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LastName  string `parquet:"name=last_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Address   string `parquet:"name=address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	City      string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Zip       string `parquet:"name=zip, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// parquetSink buffers rows into a row-group writer; Close flushes and
+// finalizes the footer, so it must always be called.
+type parquetSink struct {
+	pw *writer.ParquetWriter
+}
+
+func newParquetSink(w io.Writer) (*parquetSink, error) {
+	fw := writerfile.NewWriterFile(w)
+	pw, err := writer.NewParquetWriter(fw, new(parquetUserRow), 4)
+	if err != nil {
+		return nil, err
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetSink{pw: pw}, nil
+}
+
+func (s *parquetSink) WriteHeader() error {
+	return nil
+}
+
+// Flush is a no-op: parquet-go only writes a row group (and the final
+// footer) on WriteStop, so there is no way to push partial output to the
+// underlying writer mid-stream.
+func (s *parquetSink) Flush() error {
+	return nil
+}
+
+func (s *parquetSink) WriteRow(user User) error {
+	return s.pw.Write(parquetUserRow{
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Address:   user.Address,
+		City:      user.City,
+		Zip:       user.Zip,
+	})
+}
+
+func (s *parquetSink) Close() error {
+	if err := s.pw.WriteStop(); err != nil {
+		return err
+	}
+	return nil
+}
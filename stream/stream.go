@@ -0,0 +1,100 @@
+// Package stream runs a SQL query and renders each result row through a
+// text/template without hardcoding a Go struct or column list, so the same
+// pipeline works against any database/sql driver and schema.
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"text/template"
+)
+
+// Row is a single result row, addressable by column name ({{.email}}) or
+// position ({{index . 0}}). Both forms resolve through the same
+// interface{}-keyed map: dot access looks up the string key, and the index
+// builtin looks up the int key, so there is no separate ordered type.
+type Row map[interface{}]interface{}
+
+// StreamQuery runs sqlText against db, discovers the result schema via
+// rows.Columns()/rows.ColumnTypes(), and executes tmplText once with a
+// channel of Row values as the data, one row per {{range}} iteration.
+func StreamQuery(ctx context.Context, db *sql.DB, sqlText string, tmplText string, out io.Writer) error {
+	tmpl, err := template.New("stream").Parse(tmplText)
+	if err != nil {
+		return err
+	}
+
+	// ctx is wrapped so an early Execute error (bad template, write error)
+	// can cancel the in-flight query and unblock the producer below instead
+	// of leaking it forever on a channel nobody drains anymore.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, sqlText)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	c := make(chan Row)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(c)
+
+		// scanDest/values are allocated once and reused for every row:
+		// Scan writes through the same pointers each iteration, so there is
+		// no per-row slice allocation here.
+		values := make([]interface{}, len(columnTypes))
+		scanDest := make([]interface{}, len(columnTypes))
+		for i := range scanDest {
+			scanDest[i] = &values[i]
+		}
+
+		for rows.Next() {
+			if err := rows.Scan(scanDest...); err != nil {
+				errc <- err
+				return
+			}
+
+			row := make(Row, len(columns)*2)
+			for i, col := range columns {
+				// lib/pq and go-sql-driver/mysql return []byte for text
+				// columns (unlike go-sqlite3, which returns string); without
+				// this, text/template renders it as a numeric byte array.
+				v := values[i]
+				if b, ok := v.([]byte); ok {
+					v = string(b)
+				}
+				row[col] = v
+				row[i] = v
+			}
+
+			select {
+			case c <- row:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errc <- rows.Err()
+	}()
+
+	execErr := tmpl.Execute(out, c)
+	if execErr != nil {
+		cancel()
+		return execErr
+	}
+
+	return <-errc
+}
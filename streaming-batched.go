@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// batch is a fixed-size slice of users fetched from the DB in one Scan loop,
+// submitted as a single unit of work to the worker pool.
+type batch struct {
+	seq   int
+	users []User
+}
+
+// renderedBatch is a worker's output for a given batch, kept in a pooled
+// buffer so the ordered writer can hand it straight to os.Stdout and return
+// it to the pool once written.
+type renderedBatch struct {
+	seq int
+	buf *bytes.Buffer
+}
+
+func generateBatchedStreamingMarkdown(batchSize int, concurrency int) error {
+	eg, ctx := errgroup.WithContext(context.Background())
+
+	userSlicePool := sync.Pool{
+		New: func() interface{} {
+			s := make([]User, 0, batchSize)
+			return &s
+		},
+	}
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return new(bytes.Buffer)
+		},
+	}
+
+	tmpl, err := template.New("markdown-row").Parse(markdownRowTemplate)
+	if err != nil {
+		return err
+	}
+
+	batches := make(chan *batch, concurrency)
+	renderedCh := make(chan renderedBatch, concurrency)
+
+	// producer: scans rows into pooled []User slices and pushes them as batches
+	eg.Go(func() error {
+		defer close(batches)
+
+		db, err := sql.Open("sqlite3", "users.db")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		rows, err := db.QueryContext(ctx, `SELECT email, first_name, last_name, address, city, zip FROM users`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		seq := 0
+		users := (*userSlicePool.Get().(*[]User))[:0]
+		for rows.Next() {
+			var user User
+			if err := rows.Scan(&user.Email, &user.FirstName, &user.LastName, &user.Address, &user.City, &user.Zip); err != nil {
+				return err
+			}
+			recordRowProcessed()
+			users = append(users, user)
+			if len(users) == batchSize {
+				batches <- &batch{seq: seq, users: users}
+				seq++
+				users = (*userSlicePool.Get().(*[]User))[:0]
+			}
+		}
+		if len(users) > 0 {
+			batches <- &batch{seq: seq, users: users}
+			seq++
+		}
+
+		return rows.Err()
+	})
+
+	// fan out to K worker goroutines, each rendering its batch with a cloned template
+	var workers sync.WaitGroup
+	workers.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		eg.Go(func() error {
+			defer workers.Done()
+
+			workerTmpl, err := tmpl.Clone()
+			if err != nil {
+				return err
+			}
+
+			for b := range batches {
+				buf := bufPool.Get().(*bytes.Buffer)
+				buf.Reset()
+
+				renderStart := time.Now()
+				for _, user := range b.users {
+					if err := workerTmpl.Execute(buf, user); err != nil {
+						return err
+					}
+				}
+				recordBatchRenderLatency(time.Since(renderStart))
+
+				userSlicePool.Put(&b.users)
+				renderedCh <- renderedBatch{seq: b.seq, buf: buf}
+			}
+
+			return nil
+		})
+	}
+
+	// once every worker has drained batches, close renderedCh so the writer below can finish
+	go func() {
+		workers.Wait()
+		close(renderedCh)
+	}()
+
+	// ordered writer: emits the table header once, then pulls rendered
+	// buffers in submission order and writes them out
+	eg.Go(func() error {
+		if _, err := os.Stdout.WriteString(markdownHeader); err != nil {
+			return err
+		}
+
+		pending := map[int]*bytes.Buffer{}
+		next := 0
+
+		for r := range renderedCh {
+			pending[r.seq] = r.buf
+			for {
+				buf, ok := pending[next]
+				if !ok {
+					break
+				}
+				if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+					return err
+				}
+				delete(pending, next)
+				bufPool.Put(buf)
+				next++
+			}
+		}
+
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	log.Println("Successfully generated batched markdown table.")
+
+	err = writeProfile("mem-streaming-batched.prof")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
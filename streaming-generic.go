@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+
+	"github.com/wesen/go-template-streaming/stream"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// genericMarkdownTemplate is the default template for generateStreamQuery.
+// Unlike markdownTemplate, it addresses columns by the lowercase, DB-derived
+// names stream.Row keys its map with ({{.email}}), not capitalized User
+// struct fields ({{.Email}}).
+const genericMarkdownTemplate = `
+| Email | First Name | Last Name | Address | City | Zip |
+|-------|------------|-----------|---------|------|-----|
+{{range .}}
+| {{.email}} | {{.first_name}} | {{.last_name}} | {{.address}} | {{.city}} | {{.zip}} |{{end}}
+`
+
+// generateStreamQuery drives stream.StreamQuery against an arbitrary driver
+// and query instead of the sqlite-specific, User-shaped pipeline the other
+// generate* functions use.
+func generateStreamQuery(driver, dsn, queryText, templateFile string) error {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tmplText := genericMarkdownTemplate
+	if templateFile != "" {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return err
+		}
+		tmplText = string(data)
+	}
+
+	return stream.StreamQuery(context.Background(), db, queryText, tmplText, os.Stdout)
+}
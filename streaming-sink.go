@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"os"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// generateStreamingSink drives the producer/consumer pipeline through a Sink,
+// so the serialized output format and compression are independent of how
+// rows are fetched from the DB.
+func generateStreamingSink(format string, compress string) error {
+	eg, ctx := errgroup.WithContext(context.Background())
+
+	c := make(chan User)
+
+	eg.Go(func() error {
+		defer close(c)
+
+		db, err := sql.Open("sqlite3", "users.db")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		rows, err := db.QueryContext(ctx, `SELECT email, first_name, last_name, address, city, zip FROM users`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var user User
+			if err := rows.Scan(&user.Email, &user.FirstName, &user.LastName, &user.Address, &user.City, &user.Zip); err != nil {
+				return err
+			}
+			recordRowProcessed()
+			c <- user
+		}
+
+		return rows.Err()
+	})
+
+	eg.Go(func() error {
+		out, err := wrapCompressor(os.Stdout, compress)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		sink, err := newSink(format, out)
+		if err != nil {
+			return err
+		}
+
+		if err := sink.WriteHeader(); err != nil {
+			return err
+		}
+		for user := range c {
+			if err := sink.WriteRow(user); err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Successfully generated %s output.\n", format)
+		return sink.Close()
+	})
+
+	err := eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	err = writeProfile("mem-streaming-sink.prof")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
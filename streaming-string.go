@@ -40,6 +40,7 @@ func generateStreamingStringMarkdown() error {
 			if err := rows.Scan(&s); err != nil {
 				return err
 			}
+			recordRowProcessed()
 			c <- s
 		}
 
@@ -34,6 +34,7 @@ func generateStreamingMarkdown() error {
 			if err := rows.Scan(&user.Email, &user.FirstName, &user.LastName, &user.Address, &user.City, &user.Zip); err != nil {
 				return err
 			}
+			recordRowProcessed()
 			c <- user
 		}
 